@@ -30,13 +30,33 @@ type ClosestMatch struct {
 	SelectedColor *color.Color
 	// default: FgHiBlack
 	InstructionColor *color.Color
+
+	cm      *closestmatch.ClosestMatch
+	content []string
+}
+
+// closestMatchDelim separates a Data key from its value within content, so
+// CB and Complete can recover the title after matching against both.
+const closestMatchDelim = "::CBDELIM::"
+
+// init builds the closestmatch index once, lazily, so it's shared between
+// CB and Complete.
+func (c *ClosestMatch) init() {
+	if c.cm != nil {
+		return
+	}
+
+	for k, v := range c.Data {
+		c.content = append(c.content, strings.Join([]string{k, v}, closestMatchDelim))
+	}
+
+	bagSizes := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	c.cm = closestmatch.New(c.content, bagSizes)
 }
 
 // CB returns a configured callback to use with Prompt
 func (c *ClosestMatch) CB() Callback {
-	// We want to add context to closestmatch, but separate the titles back
-	// later
-	delim := "::CBDELIM::"
+	c.init()
 
 	if c.SelectedColor == nil {
 		c.SelectedColor = color.New(color.FgBlue)
@@ -48,19 +68,14 @@ func (c *ClosestMatch) CB() Callback {
 		c.Instructions = "Use <TAB> and <ENTER> to select from below. Otherwise press <ENTER> when ready"
 	}
 
-	var content []string
-	for k, v := range c.Data {
-		content = append(content, strings.Join([]string{k, v}, delim))
-	}
-
-	bagSizes := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
-	cm := closestmatch.New(content, bagSizes)
+	content := c.content
+	cm := c.cm
 
 	// Only recompute when needed instead of every callback invocation
 	var topN []string
 	lastSelected := -1
 	return func(inp string, tab bool, enter bool) string {
-		preproc := func(s string) string { return strings.Split(s, delim)[0] }
+		preproc := func(s string) string { return strings.Split(s, closestMatchDelim)[0] }
 
 		if enter {
 			if lastSelected != -1 {
@@ -102,6 +117,33 @@ func (c *ClosestMatch) CB() Callback {
 	}
 }
 
+// Complete implements Completer, so ClosestMatch can be driven natively by
+// Prompt.Completer instead of through CB's Callback.
+func (c *ClosestMatch) Complete(input string, tabCount int) ([]Candidate, string) {
+	c.init()
+	preproc := func(s string) string { return strings.Split(s, closestMatchDelim)[0] }
+
+	if len(c.content) == 0 {
+		return nil, input
+	}
+
+	var matches []string
+	if input == "" {
+		matches = c.content[:min(len(c.content), c.MaxShown)]
+	} else {
+		matches = c.cm.ClosestN(strings.ToLower(input), c.MaxShown)
+	}
+
+	titles := make([]string, len(matches))
+	candidates := make([]Candidate, len(matches))
+	for i, m := range matches {
+		title := preproc(m)
+		titles[i] = title
+		candidates[i] = Candidate{Display: title, Value: title}
+	}
+	return candidates, commonPrefix(titles)
+}
+
 func (c *ClosestMatch) joinLines(lines []string, preproc func(string) string, selected int) string {
 	var output string
 	if c.ShowInstructions {
@@ -0,0 +1,45 @@
+package rtprompt
+
+// Candidate is a single completion option.
+type Candidate struct {
+	// Display is shown to the user below the prompt.
+	Display string
+	// Value replaces the current input when this candidate is selected.
+	Value string
+}
+
+// Completer produces completion candidates for the current input.
+//
+// tabCount is how many times Tab has been pressed in a row, starting at 1;
+// any other key resets it. Prompt uses it to insert the longest common
+// prefix on the first Tab (readline-style) and only cycle through
+// candidates on subsequent presses.
+type Completer interface {
+	Complete(input string, tabCount int) (candidates []Candidate, commonPrefix string)
+}
+
+// commonPrefix returns the longest common prefix shared by every string in
+// ss, or "" if ss is empty.
+//
+// Compares rune by rune rather than byte by byte, so it can't split a
+// multi-byte UTF-8 sequence shared by two candidates that diverge partway
+// through it.
+func commonPrefix(ss []string) string {
+	if len(ss) == 0 {
+		return ""
+	}
+
+	prefix := []rune(ss[0])
+	for _, s := range ss[1:] {
+		r := []rune(s)
+		i := 0
+		for i < len(prefix) && i < len(r) && prefix[i] == r[i] {
+			i++
+		}
+		prefix = prefix[:i]
+		if len(prefix) == 0 {
+			return ""
+		}
+	}
+	return string(prefix)
+}
@@ -0,0 +1,67 @@
+package rtprompt
+
+import (
+	"os"
+	"strings"
+)
+
+// History stores previously submitted prompt entries so Prompt can offer
+// Up/Down navigation and Ctrl-R reverse-incremental search across runs.
+//
+// Implementations own persistence. Prompt calls Load once before reading
+// keystrokes and Append after each entry is submitted with Enter.
+type History interface {
+	// Load returns previously stored entries, oldest first.
+	Load() ([]string, error)
+
+	// Append adds entry, trimming the stored history down to max entries
+	// (no trimming when max <= 0).
+	//
+	// Implementations should skip the write entirely when entry duplicates
+	// the most recently stored entry.
+	Append(entry string, max int) error
+}
+
+// fileHistory is a newline-delimited, file-backed History.
+type fileHistory struct {
+	path string
+}
+
+// NewFileHistory returns a History that persists entries to path, one per
+// line.
+func NewFileHistory(path string) History {
+	return &fileHistory{path: path}
+}
+
+func (f *fileHistory) Load() ([]string, error) {
+	b, err := os.ReadFile(f.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	trimmed := strings.TrimRight(string(b), "\n")
+	if trimmed == "" {
+		return nil, nil
+	}
+	return strings.Split(trimmed, "\n"), nil
+}
+
+func (f *fileHistory) Append(entry string, max int) error {
+	entries, err := f.Load()
+	if err != nil {
+		return err
+	}
+
+	if n := len(entries); n > 0 && entries[n-1] == entry {
+		return nil
+	}
+
+	entries = append(entries, entry)
+	if max > 0 && len(entries) > max {
+		entries = entries[len(entries)-max:]
+	}
+	return os.WriteFile(f.path, []byte(strings.Join(entries, "\n")+"\n"), 0o600)
+}
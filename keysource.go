@@ -0,0 +1,133 @@
+package rtprompt
+
+import (
+	"bufio"
+	"io"
+	"unicode/utf8"
+
+	"github.com/coxley/keyboard"
+)
+
+// decodeKeys turns a raw byte stream into keyboard.KeyEvents, for running
+// Prompt over a reader that isn't a local tty (eg: an SSH channel) where
+// github.com/coxley/keyboard can't read directly. It understands the subset
+// of keys handleKey acts on: plain runes, Enter, the editing/navigation
+// Ctrl keys, Backspace/Delete, arrow keys, and Esc/Alt combos.
+func decodeKeys(in io.Reader) <-chan keyboard.KeyEvent {
+	ch := make(chan keyboard.KeyEvent, 10)
+	go func() {
+		defer close(ch)
+		r := bufio.NewReader(in)
+		for {
+			b, err := r.ReadByte()
+			if err != nil {
+				return
+			}
+
+			switch {
+			case b == '\r' || b == '\n':
+				ch <- keyboard.KeyEvent{Key: keyboard.KeyEnter}
+			case b == 0x03:
+				ch <- keyboard.KeyEvent{Key: keyboard.KeyCtrlC}
+			case b == '\t':
+				ch <- keyboard.KeyEvent{Key: keyboard.KeyTab}
+			case b == 0x7f || b == 0x08:
+				ch <- keyboard.KeyEvent{Key: keyboard.KeyBackspace}
+			case b == 0x01:
+				ch <- keyboard.KeyEvent{Key: keyboard.KeyCtrlA}
+			case b == 0x02:
+				ch <- keyboard.KeyEvent{Key: keyboard.KeyCtrlB}
+			case b == 0x04:
+				ch <- keyboard.KeyEvent{Key: keyboard.KeyCtrlD}
+			case b == 0x05:
+				ch <- keyboard.KeyEvent{Key: keyboard.KeyCtrlE}
+			case b == 0x06:
+				ch <- keyboard.KeyEvent{Key: keyboard.KeyCtrlF}
+			case b == 0x07:
+				ch <- keyboard.KeyEvent{Key: keyboard.KeyCtrlG}
+			case b == 0x0b:
+				ch <- keyboard.KeyEvent{Key: keyboard.KeyCtrlK}
+			case b == 0x12:
+				ch <- keyboard.KeyEvent{Key: keyboard.KeyCtrlR}
+			case b == 0x15:
+				ch <- keyboard.KeyEvent{Key: keyboard.KeyCtrlU}
+			case b == 0x17:
+				ch <- keyboard.KeyEvent{Key: keyboard.KeyCtrlW}
+			case b == 0x1b:
+				ch <- decodeEscape(r)
+			case b == ' ':
+				ch <- keyboard.KeyEvent{Key: keyboard.KeySpace, Rune: ' '}
+			default:
+				ch <- decodeRune(b, r)
+			}
+		}
+	}()
+	return ch
+}
+
+// decodeEscape reads the remainder of an ANSI escape sequence following a
+// 0x1b byte already consumed from r, falling back to a bare/Alt-prefixed Esc
+// for anything it doesn't recognize.
+func decodeEscape(r *bufio.Reader) keyboard.KeyEvent {
+	b1, err := r.ReadByte()
+	if err != nil {
+		return keyboard.KeyEvent{Key: keyboard.KeyEsc}
+	}
+	if b1 != '[' {
+		return keyboard.KeyEvent{Key: keyboard.KeyEsc, Rune: rune(b1)}
+	}
+
+	b2, err := r.ReadByte()
+	if err != nil {
+		return keyboard.KeyEvent{Key: keyboard.KeyEsc}
+	}
+	switch b2 {
+	case 'A':
+		return keyboard.KeyEvent{Key: keyboard.KeyArrowUp}
+	case 'B':
+		return keyboard.KeyEvent{Key: keyboard.KeyArrowDown}
+	case 'C':
+		return keyboard.KeyEvent{Key: keyboard.KeyArrowRight}
+	case 'D':
+		return keyboard.KeyEvent{Key: keyboard.KeyArrowLeft}
+	case 'H':
+		return keyboard.KeyEvent{Key: keyboard.KeyHome}
+	case 'F':
+		return keyboard.KeyEvent{Key: keyboard.KeyEnd}
+	case '3':
+		r.ReadByte() // consume the trailing '~' of "\x1b[3~"
+		return keyboard.KeyEvent{Key: keyboard.KeyDelete}
+	default:
+		return keyboard.KeyEvent{Key: keyboard.KeyEsc}
+	}
+}
+
+// decodeRune decodes a (possibly multi-byte UTF-8) rune starting with b,
+// which has already been consumed from r.
+func decodeRune(b byte, r *bufio.Reader) keyboard.KeyEvent {
+	if b < utf8.RuneSelf {
+		return keyboard.KeyEvent{Rune: rune(b)}
+	}
+
+	n := 0
+	switch {
+	case b&0xE0 == 0xC0:
+		n = 1
+	case b&0xF0 == 0xE0:
+		n = 2
+	case b&0xF8 == 0xF0:
+		n = 3
+	}
+
+	buf := make([]byte, 1, 1+n)
+	buf[0] = b
+	for i := 0; i < n; i++ {
+		nb, err := r.ReadByte()
+		if err != nil {
+			break
+		}
+		buf = append(buf, nb)
+	}
+	rn, _ := utf8.DecodeRune(buf)
+	return keyboard.KeyEvent{Rune: rn}
+}
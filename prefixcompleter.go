@@ -0,0 +1,81 @@
+package rtprompt
+
+import "sort"
+
+// trieNode is a single node in a PrefixCompleter's trie.
+type trieNode struct {
+	children map[rune]*trieNode
+	terminal bool
+	value    string // set when terminal, the stored entry
+}
+
+func newTrieNode() *trieNode {
+	return &trieNode{children: make(map[rune]*trieNode)}
+}
+
+// PrefixCompleter completes structured input, such as filesystem paths or
+// command names, by matching on a literal prefix rather than the fuzzy
+// similarity ClosestMatch uses.
+type PrefixCompleter struct {
+	root *trieNode
+}
+
+// NewPrefixCompleter builds a PrefixCompleter from entries, eg: file paths
+// or command names.
+func NewPrefixCompleter(entries []string) *PrefixCompleter {
+	p := &PrefixCompleter{root: newTrieNode()}
+	for _, e := range entries {
+		p.Add(e)
+	}
+	return p
+}
+
+// Add inserts a new entry into the trie.
+func (p *PrefixCompleter) Add(entry string) {
+	n := p.root
+	for _, r := range entry {
+		next, ok := n.children[r]
+		if !ok {
+			next = newTrieNode()
+			n.children[r] = next
+		}
+		n = next
+	}
+	n.terminal = true
+	n.value = entry
+}
+
+// Complete implements Completer by walking to the node for input, then
+// collecting every entry beneath it.
+func (p *PrefixCompleter) Complete(input string, tabCount int) ([]Candidate, string) {
+	n := p.root
+	for _, r := range input {
+		next, ok := n.children[r]
+		if !ok {
+			return nil, input
+		}
+		n = next
+	}
+
+	var matches []string
+	collectEntries(n, &matches)
+	if len(matches) == 0 {
+		return nil, input
+	}
+	sort.Strings(matches)
+
+	candidates := make([]Candidate, len(matches))
+	for i, m := range matches {
+		candidates[i] = Candidate{Display: m, Value: m}
+	}
+	return candidates, commonPrefix(matches)
+}
+
+func collectEntries(n *trieNode, out *[]string) {
+	if n.terminal {
+		*out = append(*out, n.value)
+	}
+	for _, child := range n.children {
+		collectEntries(child, out)
+	}
+}
@@ -2,11 +2,12 @@ package rtprompt
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"strings"
+	"sync"
 
 	"github.com/coxley/keyboard"
-	"golang.org/x/term"
 )
 
 // Callback to notify when input changes
@@ -26,6 +27,19 @@ import (
 // is something you want please submit an issue on the repo. :)
 type Callback func(s string, tab bool, enter bool) string
 
+// RawModeFunc puts the terminal backing a Prompt's In/Out into raw
+// (character-at-a-time, unechoed) mode and returns a function that restores
+// it.
+//
+// The default (nil) reads keypresses from the local tty through
+// github.com/coxley/keyboard and, on Unix, puts fd 0 into raw mode with
+// golang.org/x/term, which is right for the common case of a local tty on os.Stdin/os.Stdout. Set this
+// when In/Out aren't a local tty, eg: an SSH channel that's already
+// unbuffered once a pty-req succeeds, where there's nothing local to
+// restore. When set, keypresses are decoded from In directly instead of
+// through github.com/coxley/keyboard, which only reads a real tty.
+type RawModeFunc func() (restore func(), err error)
+
 // Prompt lets a user type input that is sent to a callback in realtime
 //
 // Callback is invoked when the input changes or Tab or Enter are pressed.  The
@@ -44,22 +58,82 @@ type Prompt struct {
 	// Keep the value of text and pos variables on screen
 	Debug bool
 
-	text string
-	pos  int  // position of cursor
-	tab  bool // Did the user just press tab?
+	// Optional persistent history. When set, Up/Down navigate past entries
+	// and Ctrl-R starts a reverse-incremental search, similar to readline.
+	History History
+
+	// Maximum number of entries kept in History (default: 500)
+	MaxHistory int
+
+	// Optional completer driven natively by Tab, rendered below the prompt
+	// the same way Callback output is. The first Tab inserts the longest
+	// common prefix of the candidates; subsequent Tabs cycle through them.
+	Completer Completer
+
+	// Optional syntax highlighter for the input line itself, invoked with
+	// the current text and cursor position on every change. Its return
+	// value is drawn in place of the raw text, so it should re-emit text
+	// with ANSI color codes inserted rather than replacing any of it.
+	Highlighter func(text string, pos int) string
+
+	// In and Out default to os.Stdin and os.Stdout. Override to run Prompt
+	// over another stream, eg: an SSH channel (see NewSSH) or a test's byte
+	// buffers. In is only read when RawMode is set; otherwise keypresses come
+	// from the local tty.
+	In  io.Reader
+	Out io.Writer
+
+	// RawMode overrides how the terminal is put into (and restored from)
+	// raw mode. See RawModeFunc.
+	RawMode RawModeFunc
+
+	text []rune // runes, not bytes, so multi-byte/wide characters stay correct
+	pos  int    // position of cursor, in runes
+	tab  bool   // Did the user just press tab?
+
+	// How many lines of callback/search output are currently on screen, so
+	// they can be cleared before the next redraw.
+	lastOutputLines int
+
+	history     []string // loaded + accumulated entries, oldest first
+	historyIdx  int      // index into history while navigating; == len(history) means "not navigating"
+	historyTemp string   // in-progress text stashed when navigation starts
+
+	searching   bool
+	searchQuery string
+	searchFrom  int    // index to search backward from
+	searchStash string // in-progress text stashed when search starts
+	searchHit   string // match renderSearch last showed, "" if none
+
+	completeTab  int // consecutive Tab presses against Completer for the current input
+	completeIdx  int // index into the last candidates returned, while cycling
+	completeBase string
 
 	// How many lines did we write on? We'll need to clear them when finished,
 	// else Bash prompts will be unhappy.
 	writtenLineCnt int
+
+	// Platform-specific raw mode + cursor control. Set lazily in readInput so
+	// zero-value Prompts (eg: in tests) don't touch the terminal.
+	//
+	// termMu guards term itself (not the terminal interface's methods, which
+	// are only ever called from readInput's goroutine; the SetSize that
+	// setSize calls has its own lock): NewSSH's reqs-handling goroutine calls
+	// setSize, which reads term, concurrently with readInput assigning it.
+	termMu sync.Mutex
+	term   terminal
 }
 
 // New prompt instance w/ sane defaults
 func New(pfx string, callback func(string, bool, bool) string) *Prompt {
 	return &Prompt{
-		Prefix:   pfx,
-		Callback: callback,
-		Padding:  2,
-		Debug:    false,
+		Prefix:     pfx,
+		Callback:   callback,
+		Padding:    2,
+		Debug:      false,
+		MaxHistory: 500,
+		In:         os.Stdin,
+		Out:        os.Stdout,
 	}
 }
 
@@ -86,42 +160,47 @@ func (p *Prompt) Wait() {
 
 // Set terminal mode to raw, set up goroutines, and start reading keypresses
 func (p *Prompt) readInput() {
+	p.termMu.Lock()
+	p.term = newTerminal(p.Out)
+	p.termMu.Unlock()
 
 	// Terminal must be set to raw mode
-	oldState, err := term.MakeRaw(0)
+	keyCh, err := p.term.Open(p.In, p.RawMode)
 	if err != nil {
 		panic(err)
 	}
-	defer term.Restore(0, oldState)
+	defer p.term.Close()
 
 	// define cleanup so we can use before SIGINT too
 	cleanupFunc := func() {
-		term.Restore(0, oldState)
+		p.term.Close()
 		// Bash doesn't auto-clear lines beneath the prompt when a command
 		// ends. Let's clear what we've output so far from callback.
 		p.print(strings.Repeat("\n", p.writtenLineCnt), 1)
-		fmt.Println()
+		fmt.Fprintln(p.Out)
 	}
 	defer cleanupFunc()
 
-	keyCh, err := keyboard.GetKeys(10)
-	if err != nil {
-		panic(err)
+	if p.History != nil {
+		entries, err := p.History.Load()
+		if err != nil {
+			p.print(fmt.Sprintf("history load error: %+v", err), 10)
+		}
+		p.history = entries
 	}
-	defer keyboard.Close()
+	p.historyIdx = len(p.history)
 
 	// We should erase previously output lines before rewriting
-	var lastOutputLines int
 	handleCB := func(s string, tab bool, enter bool) {
 		out := p.Callback(s, tab, enter)
 
-		clearLines(lastOutputLines, p.Padding)
+		p.clearLines(p.lastOutputLines, p.Padding)
 		p.print(out, p.Padding)
-		lastOutputLines = strings.Count(out, "\n")
+		p.lastOutputLines = strings.Count(out, "\n")
 	}
 
 	// Prompt statement + initial output from callback
-	fmt.Printf(p.Prefix)
+	fmt.Fprint(p.Out, p.Prefix)
 	handleCB("", false, false)
 	for {
 		select {
@@ -130,12 +209,9 @@ func (p *Prompt) readInput() {
 				p.print(fmt.Sprintf("error: %+v", e), 10)
 			}
 
-			// Should we finish?
-			if e.Key == keyboard.KeyEnter {
-				handleCB(p.text, false, true)
-				return
-			}
-
+			// Ctrl-C should always raise SIGINT, search mode included -
+			// check it before the p.searching branch so a stuck search
+			// doesn't need an Esc/Ctrl-G first.
 			if e.Key == keyboard.KeyCtrlC {
 				cleanupFunc()
 				// always succeeds on UNIX systems
@@ -143,20 +219,48 @@ func (p *Prompt) readInput() {
 				p.Signal(os.Interrupt)
 			}
 
+			if p.searching {
+				if p.handleSearchKey(e) {
+					handleCB(string(p.text), false, false)
+				}
+				continue
+			}
+
+			if e.Key != keyboard.KeyTab {
+				p.completeTab = 0
+			}
+
+			// Should we finish?
+			if e.Key == keyboard.KeyEnter {
+				p.recordHistory()
+				handleCB(string(p.text), false, true)
+				return
+			}
+
 			// Tab is pressed, no need to handle other keys
 			if e.Key == keyboard.KeyTab {
-				handleCB(p.text, true, false)
+				if p.Completer != nil {
+					p.completeTab++
+					p.complete()
+				} else {
+					handleCB(string(p.text), true, false)
+				}
+				continue
+			}
+
+			if e.Key == keyboard.KeyCtrlR && p.History != nil {
+				p.startSearch()
 				continue
 			}
 
 			// Don't update callback for text navigation. (arrow keys, etc)
-			oldText := p.text
+			oldText := string(p.text)
 			p.handleKey(e)
-			if p.text == oldText {
+			if string(p.text) == oldText {
 				continue
 			}
 
-			handleCB(p.text, false, false)
+			handleCB(string(p.text), false, false)
 		}
 	}
 }
@@ -185,6 +289,10 @@ func (p *Prompt) handleKey(key keyboard.KeyEvent) {
 		p.cursorLeft(1)
 	case keyboard.KeyArrowRight, keyboard.KeyCtrlF:
 		p.cursorRight(1)
+	case keyboard.KeyArrowUp:
+		p.historyPrev()
+	case keyboard.KeyArrowDown:
+		p.historyNext()
 	case keyboard.KeyBackspace, keyboard.KeyBackspace2:
 		p.backspace(1)
 	case keyboard.KeyDelete, keyboard.KeyCtrlD:
@@ -214,7 +322,7 @@ func (p *Prompt) handleKey(key keyboard.KeyEvent) {
 	}
 
 	if p.Debug {
-		debugText := fmt.Sprintf("text=%v\npos=%v\n", p.text, p.pos)
+		debugText := fmt.Sprintf("text=%v\npos=%v\n", string(p.text), p.pos)
 		p.print(debugText, 15)
 	}
 }
@@ -240,7 +348,7 @@ func (p *Prompt) print(s string, padding int) {
 	}
 	// Create padding lines, but don't clear as there's no content.
 	for i := 0; i < padding; i++ {
-		fmt.Printf("\n")
+		fmt.Fprintf(p.Out, "\n")
 	}
 	// Create enough space for the output.
 	//
@@ -251,49 +359,49 @@ func (p *Prompt) print(s string, padding int) {
 	// For each line we create, clear it to allow new text to replace it
 	// entirely.
 	linecnt := strings.Count(s, "\n")
-	clearLine() // otherwise the first line of 's' won't be a clean slate
+	p.term.ClearLine() // otherwise the first line of 's' won't be a clean slate
 	for i := 0; i < linecnt; i++ {
-		fmt.Printf("\n")
-		clearLine()
+		fmt.Fprintf(p.Out, "\n")
+		p.term.ClearLine()
 	}
 
 	p.writtenLineCnt = max(p.writtenLineCnt, linecnt+padding)
 
 	// Go back to where we started, and save it.
-	cursorUp(linecnt + padding)
-	saveCursor()
+	p.term.CursorUp(linecnt + padding)
+	p.term.SaveCursor()
 
 	// \r in ANSI moves cursor to beginning of current line. Default goes down
 	// a row without changing column position.
 	//
 	// \n without \r will make paragraphs look like a waterfall. Not a typo for
 	// CRLF
-	fmt.Print(strings.Repeat("\n\r", padding))
-	fmt.Print(strings.ReplaceAll(s, "\n", "\n\r"))
-	restoreCursor()
+	fmt.Fprint(p.Out, strings.Repeat("\n\r", padding))
+	fmt.Fprint(p.Out, strings.ReplaceAll(s, "\n", "\n\r"))
+	p.term.RestoreCursor()
 }
 
-// move and update position of cursor
+// move and update position of cursor, n runes to the left
 func (p *Prompt) cursorLeft(n int) {
 	// At bounds, no-op
-	if p.pos == 0 {
+	if p.pos == 0 || n <= 0 {
 		return
 	}
-	fmt.Printf("\033[%dD", n)
+	p.term.CursorLeft(runesWidth(p.text[p.pos-n : p.pos]))
 	p.pos -= n
 }
 
-// move and update position of cursor
+// move and update position of cursor, n runes to the right
 func (p *Prompt) cursorRight(n int) {
 	// At bounds, no-op
-	if p.pos == len(p.text) {
+	if p.pos == len(p.text) || n <= 0 {
 		return
 	}
-	fmt.Printf("\033[%dC", n)
+	p.term.CursorRight(runesWidth(p.text[p.pos : p.pos+n]))
 	p.pos += n
 }
 
-// delete text behind the cursor
+// delete n runes behind the cursor
 func (p *Prompt) backspace(n int) {
 	if n == 0 {
 		return
@@ -309,18 +417,25 @@ func (p *Prompt) backspace(n int) {
 	start := p.text[:newPos]
 	end := p.text[oldPos:]
 
+	if p.Highlighter != nil {
+		p.text = append(append([]rune{}, start...), end...)
+		p.pos = newPos
+		p.redrawLine()
+		return
+	}
+
 	// Clear all text from new position to old position
 	p.cursorLeft(n)
-	saveCursor()
-	eraseFromCursor()
+	p.term.SaveCursor()
+	p.eraseToEnd(p.column(p.pos), end)
 
-	fmt.Printf(end)
-	p.text = start + end
+	fmt.Fprint(p.Out, string(end))
+	p.text = append(append([]rune{}, start...), end...)
 	p.pos = newPos
-	restoreCursor()
+	p.term.RestoreCursor()
 }
 
-// delete text in front of the cursor
+// delete n runes in front of the cursor
 func (p *Prompt) del(n int) {
 	if n == 0 {
 		return
@@ -333,14 +448,21 @@ func (p *Prompt) del(n int) {
 
 	start := p.text[:p.pos]
 	end := p.text[p.pos+n:]
-	saveCursor()
+
+	if p.Highlighter != nil {
+		p.text = append(append([]rune{}, start...), end...)
+		p.redrawLine()
+		return
+	}
+
+	p.term.SaveCursor()
 
 	// Clear all text from new position to old position
-	eraseFromCursor()
-	fmt.Printf(end)
-	p.text = start + end
+	p.eraseToEnd(p.column(p.pos), end)
+	fmt.Fprint(p.Out, string(end))
+	p.text = append(append([]rune{}, start...), end...)
 
-	restoreCursor()
+	p.term.RestoreCursor()
 }
 
 // add text in front of the prompt and advance the cursor's position
@@ -348,76 +470,310 @@ func (p *Prompt) advance(s string) {
 	if s == "" {
 		return
 	}
+	add := []rune(s)
 
 	// Prompt is adding text to the end
 	if p.pos == len(p.text) {
-		p.text += s
-		p.pos += len(s)
-		fmt.Printf(s)
+		p.text = append(p.text, add...)
+		p.pos += len(add)
+		if p.Highlighter != nil {
+			p.redrawLine()
+		} else {
+			fmt.Fprint(p.Out, s)
+		}
 		return
 	}
 
 	// Cursor is in the middle of the string. Divide into two parts.
 	before := p.text[:p.pos]
 	after := p.text[p.pos:]
+	newText := append(append(append([]rune{}, before...), add...), after...)
+
+	if p.Highlighter != nil {
+		p.text = newText
+		p.pos += len(add)
+		p.redrawLine()
+		return
+	}
 
 	// Clear all text from cursor onward, replace with modified text, and
 	// advance cursor.
-	saveCursor()
-	eraseFromCursor()
-	fmt.Printf(s + after)
-	p.text = before + s + after
-	restoreCursor()
-	p.cursorRight(1)
+	p.term.SaveCursor()
+	p.eraseToEnd(p.column(p.pos), after)
+	fmt.Fprint(p.Out, s+string(after))
+	p.text = newText
+	p.term.RestoreCursor()
+	p.cursorRight(len(add))
 }
 
-// erase everything on the line in front of the cursor
-func eraseFromCursor() {
-	fmt.Printf("\033[K")
+// column returns the screen column of offset runes into the current text
+// (0 for the start of input, p.pos for the cursor), accounting for line
+// wrap against the terminal width.
+func (p *Prompt) column(offset int) int {
+	total := runesWidth([]rune(p.Prefix)) + runesWidth(p.text[:offset])
+
+	width, _, err := p.term.Size()
+	if err != nil || width <= 0 {
+		return total
+	}
+	return total % width
 }
 
-// clear entire line without changing cursor position
-func clearLine() {
-	fmt.Printf("\033[2K")
+// eraseToEnd clears from the cursor to the end of the current screen line,
+// plus any further rows that remaining wraps onto, so nothing is left
+// behind when the redrawn line is shorter. col is the cursor's current
+// screen column, from column(). The cursor is left where it started.
+func (p *Prompt) eraseToEnd(col int, remaining []rune) {
+	p.term.EraseFromCursor()
+
+	width, _, err := p.term.Size()
+	if err != nil || width <= 0 {
+		return
+	}
+
+	rows := (col + runesWidth(remaining)) / width
+	if rows == 0 {
+		return
+	}
+	p.term.SaveCursor()
+	for i := 0; i < rows; i++ {
+		p.term.CursorDown(1)
+		p.term.ClearLine()
+	}
+	p.term.RestoreCursor()
+}
+
+// redrawLine fully repaints the input line through Highlighter, then
+// repositions the cursor at the logical (unstyled) rune offset p.pos.
+//
+// Only used when Highlighter is set: it trades the incremental in-place
+// edits above for a full repaint on every keystroke, since colors can
+// depend on the whole line rather than just what changed.
+func (p *Prompt) redrawLine() {
+	if col := runesWidth(p.text[:p.pos]); col > 0 {
+		p.term.CursorLeft(col)
+	}
+	p.eraseToEnd(p.column(0), p.text)
+
+	fmt.Fprint(p.Out, p.Highlighter(string(p.text), p.pos))
+
+	if col := runesWidth(p.text[p.pos:]); col > 0 {
+		p.term.CursorLeft(col)
+	}
 }
 
 // clear n lines, starting after padding lines, and restores cursor
-func clearLines(n int, padding int) {
-	saveCursor()
+func (p *Prompt) clearLines(n int, padding int) {
+	p.term.SaveCursor()
 	for i := 0; i < padding; i++ {
-		cursorDown(1)
+		p.term.CursorDown(1)
 	}
 	for i := 0; i < n; i++ {
-		clearLine()
-		cursorDown(1)
+		p.term.ClearLine()
+		p.term.CursorDown(1)
 	}
-	restoreCursor()
+	p.term.RestoreCursor()
 }
 
-// move cursor up in the same column
-func cursorUp(n int) {
-	fmt.Printf("\033[%dA", n)
+// recordHistory appends the current text to history, skipping empty input
+// and duplicates of the most recent entry, then persists it via p.History.
+func (p *Prompt) recordHistory() {
+	if p.History == nil || len(p.text) == 0 {
+		return
+	}
+	text := string(p.text)
+	if n := len(p.history); n > 0 && p.history[n-1] == text {
+		return
+	}
+
+	p.history = append(p.history, text)
+	if p.MaxHistory > 0 && len(p.history) > p.MaxHistory {
+		p.history = p.history[len(p.history)-p.MaxHistory:]
+	}
+	if err := p.History.Append(text, p.MaxHistory); err != nil {
+		p.print(fmt.Sprintf("history save error: %+v", err), 10)
+	}
 }
 
-// move cursor down in the same column
-func cursorDown(n int) {
-	fmt.Printf("\033[%dB", n)
+// setText replaces the entire buffer with s, redrawing the line and leaving
+// the cursor at the end.
+func (p *Prompt) setText(s string) {
+	p.cursorLeft(p.pos)
+	p.eraseToEnd(p.column(0), p.text)
+
+	p.text = []rune(s)
+	p.pos = len(p.text)
+
+	if p.Highlighter != nil {
+		fmt.Fprint(p.Out, p.Highlighter(s, p.pos))
+		return
+	}
+	fmt.Fprint(p.Out, s)
 }
 
-func saveCursor() {
-	fmt.Printf("\033[s")
+// historyPrev moves to the previous (older) entry, stashing the in-progress
+// text the first time it's called.
+func (p *Prompt) historyPrev() {
+	if p.historyIdx == 0 || p.historyIdx > len(p.history) {
+		return
+	}
+	if p.historyIdx == len(p.history) {
+		p.historyTemp = string(p.text)
+	}
+	p.historyIdx--
+	p.setText(p.history[p.historyIdx])
 }
 
-func restoreCursor() {
-	fmt.Printf("\033[u")
+// historyNext moves to the next (newer) entry, restoring the stashed
+// in-progress text once the newest entry is passed.
+func (p *Prompt) historyNext() {
+	if p.historyIdx >= len(p.history) {
+		return
+	}
+	p.historyIdx++
+	if p.historyIdx == len(p.history) {
+		p.setText(p.historyTemp)
+		return
+	}
+	p.setText(p.history[p.historyIdx])
+}
+
+// startSearch begins a reverse-incremental search over history, triggered by
+// Ctrl-R.
+func (p *Prompt) startSearch() {
+	p.searching = true
+	p.searchQuery = ""
+	p.searchFrom = len(p.history)
+	p.searchStash = string(p.text)
+	p.renderSearch()
+}
+
+// handleSearchKey processes a keypress while in reverse-incremental-search
+// mode. It returns true once search mode has ended, so the caller can
+// refresh the normal callback output.
+func (p *Prompt) handleSearchKey(e keyboard.KeyEvent) bool {
+	switch e.Key {
+	case keyboard.KeyCtrlG, keyboard.KeyEsc:
+		p.setText(p.searchStash)
+		p.searching = false
+		return true
+	case keyboard.KeyEnter:
+		// Accept the match on screen; calling searchMatch again would move
+		// on to the next older one.
+		if p.searchHit != "" {
+			p.setText(p.searchHit)
+		}
+		p.searching = false
+		return true
+	case keyboard.KeyCtrlR:
+		// Cycle to an older match on repeated Ctrl-R.
+		p.renderSearch()
+	case keyboard.KeyBackspace, keyboard.KeyBackspace2:
+		if len(p.searchQuery) > 0 {
+			q := []rune(p.searchQuery)
+			p.searchQuery = string(q[:len(q)-1])
+		}
+		p.searchFrom = len(p.history)
+		p.renderSearch()
+	default:
+		if e.Rune == 0 {
+			return false
+		}
+		p.searchQuery += string(e.Rune)
+		p.searchFrom = len(p.history)
+		p.renderSearch()
+	}
+	return false
+}
+
+// searchMatch returns the most recent history entry, older than searchFrom,
+// that contains searchQuery. A match advances searchFrom so the next
+// Ctrl-R continues further back.
+func (p *Prompt) searchMatch() (string, bool) {
+	if p.searchQuery == "" {
+		return "", false
+	}
+	for i := p.searchFrom - 1; i >= 0; i-- {
+		if strings.Contains(p.history[i], p.searchQuery) {
+			p.searchFrom = i
+			return p.history[i], true
+		}
+	}
+	return "", false
+}
+
+// renderSearch draws the reverse-i-search status line in place of the
+// callback output, reusing the same print/clearLines padding machinery.
+func (p *Prompt) renderSearch() {
+	match, _ := p.searchMatch()
+	p.searchHit = match
+	status := fmt.Sprintf("(reverse-i-search)`%s': %s", p.searchQuery, match)
+
+	p.clearLines(p.lastOutputLines, p.Padding)
+	p.print(status, p.Padding)
+	p.lastOutputLines = strings.Count(status, "\n")
+}
+
+// complete drives Tab-completion against p.Completer: the first Tab inserts
+// the longest common prefix of the candidates (readline-style), and every
+// Tab after that cycles through them one at a time.
+func (p *Prompt) complete() {
+	if p.completeTab == 1 {
+		p.completeBase = string(p.text)
+		p.completeIdx = -1
+	}
+
+	candidates, prefix := p.Completer.Complete(p.completeBase, p.completeTab)
+
+	if p.completeTab == 1 {
+		if len([]rune(prefix)) > len([]rune(p.completeBase)) {
+			p.setText(prefix)
+		}
+		p.renderCandidates(candidates, -1)
+		return
+	}
+
+	if len(candidates) == 0 {
+		return
+	}
+	p.completeIdx = (p.completeIdx + 1) % len(candidates)
+	p.setText(candidates[p.completeIdx].Value)
+	p.renderCandidates(candidates, p.completeIdx)
+}
+
+// renderCandidates draws completion candidates below the prompt, reusing
+// the same print/clearLines padding machinery as Callback output.
+func (p *Prompt) renderCandidates(candidates []Candidate, selected int) {
+	var b strings.Builder
+	for i, c := range candidates {
+		if i == selected {
+			fmt.Fprintf(&b, "%s (selected)\n", c.Display)
+			continue
+		}
+		fmt.Fprintln(&b, c.Display)
+	}
+	out := b.String()
+
+	p.clearLines(p.lastOutputLines, p.Padding)
+	p.print(out, p.Padding)
+	p.lastOutputLines = strings.Count(out, "\n")
 }
 
 // Look for closest space before current position (or start)
 //
 // This is used for move/remove back a word actions.
 func (p *Prompt) lastWordIndex() int {
-	// Trim right-space so that "this is a test " -> "this is a "
-	return strings.LastIndex(strings.TrimRight(p.text[:p.pos], " "), " ")
+	// Trim trailing spaces so that "this is a test " -> "this is a "
+	end := p.pos
+	for end > 0 && p.text[end-1] == ' ' {
+		end--
+	}
+	for i := end - 1; i >= 0; i-- {
+		if p.text[i] == ' ' {
+			return i
+		}
+	}
+	return -1
 }
 
 // Look for closest space after the current position (or end)
@@ -425,17 +781,28 @@ func (p *Prompt) lastWordIndex() int {
 // This is used for move/remove forward a word actions.
 func (p *Prompt) nextWordIndex() int {
 	// Split at position, but add index to the final value
-	beforeLen := len(p.text[:p.pos])
 	fwd := p.text[p.pos:]
 
-	if i := strings.Index(fwd, " "); i != 0 {
-		return i + beforeLen
+	if i := indexRune(fwd, ' '); i != 0 {
+		return i + p.pos
 	} else if i == -1 {
 		return 0
 	}
 
 	// Cursor is at a space. Trim so we can find the closest word after.
-	trimmed := strings.TrimLeft(fwd, " ")
-	spaceCnt := len(fwd) - len(trimmed)
-	return strings.Index(fwd, " ") + spaceCnt + beforeLen
+	spaceCnt := 0
+	for spaceCnt < len(fwd) && fwd[spaceCnt] == ' ' {
+		spaceCnt++
+	}
+	return indexRune(fwd, ' ') + spaceCnt + p.pos
+}
+
+// indexRune returns the index of the first occurrence of target in rs, or -1.
+func indexRune(rs []rune, target rune) int {
+	for i, r := range rs {
+		if r == target {
+			return i
+		}
+	}
+	return -1
 }
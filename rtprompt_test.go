@@ -0,0 +1,37 @@
+package rtprompt
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestPromptOverStream drives Prompt entirely through In/Out instead of the
+// local terminal, which chunk0-6 made possible: RawMode stubbed to a no-op
+// stands in for a channel (eg: SSH) that's already unbuffered, and In/Out
+// are plain buffers instead of a tty.
+func TestPromptOverStream(t *testing.T) {
+	in := strings.NewReader("hi\r")
+	var out strings.Builder
+
+	p := New("> ", nil)
+	p.In = in
+	p.Out = &out
+	p.RawMode = func() (func(), error) { return func() {}, nil }
+
+	p.Wait()
+
+	if got, want := string(p.text), "hi"; got != want {
+		t.Errorf("p.text = %q, want %q", got, want)
+	}
+
+	// Cursor-control escapes land between keystrokes (see clearLines), so
+	// don't assert on the exact byte sequence - just that both typed
+	// characters and the prefix made it through to Out.
+	got := out.String()
+	if !strings.HasPrefix(got, p.Prefix) {
+		t.Errorf("rendered output %q missing prefix %q", got, p.Prefix)
+	}
+	if !strings.Contains(got, "h") || !strings.Contains(got, "i") {
+		t.Errorf("rendered output %q missing typed characters", got)
+	}
+}
@@ -0,0 +1,103 @@
+package rtprompt
+
+import "golang.org/x/crypto/ssh"
+
+// NewSSH wires a Prompt to run over an SSH channel instead of the local
+// terminal: In and Out are the channel itself, and RawMode is a no-op,
+// since a channel that's accepted a pty-req is already unbuffered with no
+// local terminal state to restore on Close.
+//
+// reqs should be the channel's out-of-band request channel, as returned
+// alongside ch from (ssh.NewChannel).Accept. pty-req and window-change
+// requests are handled to size the prompt for line-wrap accounting; every
+// other request type is replied to negatively if it wants a reply, so the
+// client doesn't hang waiting on eg: a shell request this isn't meant to
+// service.
+func NewSSH(pfx string, callback func(string, bool, bool) string, ch ssh.Channel, reqs <-chan *ssh.Request) *Prompt {
+	p := New(pfx, callback)
+	p.In = ch
+	p.Out = ch
+	p.RawMode = func() (func(), error) { return func() {}, nil }
+
+	go func() {
+		for req := range reqs {
+			switch req.Type {
+			case "pty-req":
+				width, height, ok := parsePtyReq(req.Payload)
+				if ok {
+					p.setSize(width, height)
+				}
+				if req.WantReply {
+					req.Reply(ok, nil)
+				}
+			case "window-change":
+				width, height, ok := parseWindowChange(req.Payload)
+				if ok {
+					p.setSize(width, height)
+				}
+				if req.WantReply {
+					req.Reply(ok, nil)
+				}
+			default:
+				if req.WantReply {
+					req.Reply(false, nil)
+				}
+			}
+		}
+	}()
+
+	return p
+}
+
+// setSize forwards a terminal size update to p.term, if it's been opened and
+// supports it. Sizes that arrive before Wait calls readInput (or after it
+// returns) are silently dropped.
+//
+// This runs on NewSSH's reqs-handling goroutine, concurrently with
+// readInput assigning p.term on the goroutine that calls Wait, so the read
+// goes through termMu.
+func (p *Prompt) setSize(width, height int) {
+	p.termMu.Lock()
+	t := p.term
+	p.termMu.Unlock()
+
+	if t == nil {
+		return
+	}
+	if r, ok := t.(interface{ SetSize(int, int) }); ok {
+		r.SetSize(width, height)
+	}
+}
+
+// ptyReqPayload mirrors the "pty-req" request body from RFC 4254 section
+// 6.2. Modes holds the encoded terminal modes trailing it, which are parsed
+// only because ssh.Unmarshal rejects unconsumed bytes; they aren't used.
+type ptyReqPayload struct {
+	Term                    string
+	Width, Height           uint32
+	PixelWidth, PixelHeight uint32
+	Modes                   string
+}
+
+func parsePtyReq(payload []byte) (width, height int, ok bool) {
+	var p ptyReqPayload
+	if err := ssh.Unmarshal(payload, &p); err != nil {
+		return 0, 0, false
+	}
+	return int(p.Width), int(p.Height), true
+}
+
+// windowChangePayload mirrors the "window-change" request body from RFC
+// 4254 section 6.7.
+type windowChangePayload struct {
+	Width, Height           uint32
+	PixelWidth, PixelHeight uint32
+}
+
+func parseWindowChange(payload []byte) (width, height int, ok bool) {
+	var p windowChangePayload
+	if err := ssh.Unmarshal(payload, &p); err != nil {
+		return 0, 0, false
+	}
+	return int(p.Width), int(p.Height), true
+}
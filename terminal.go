@@ -0,0 +1,40 @@
+package rtprompt
+
+import (
+	"io"
+
+	"github.com/coxley/keyboard"
+)
+
+// terminal abstracts the platform-specific pieces of raw-mode input and
+// cursor control so Prompt can run on Unix and Windows alike.
+//
+// The Unix implementation (terminal_unix.go) is a thin wrapper around
+// golang.org/x/term and ANSI escape sequences. The Windows implementation
+// (terminal_windows.go) enables ENABLE_VIRTUAL_TERMINAL_PROCESSING where
+// available and otherwise falls back to the console API, mirroring what
+// peterh/liner does in input_windows.go/output_windows.go. Both embed
+// streamTerminal (terminal_stream.go) for the case where rawMode is set and
+// there's no local tty at all, eg: an SSH channel.
+type terminal interface {
+	// Open starts reading keypresses from in and puts the terminal into raw
+	// mode: the platform default when rawMode is nil, or whatever rawMode
+	// does otherwise.
+	Open(in io.Reader, rawMode RawModeFunc) (<-chan keyboard.KeyEvent, error)
+	// Close restores the terminal to its original mode.
+	Close()
+
+	CursorUp(n int)
+	CursorDown(n int)
+	CursorLeft(n int)
+	CursorRight(n int)
+	ClearLine()
+	EraseFromCursor()
+	SaveCursor()
+	RestoreCursor()
+
+	// Size returns the terminal's width and height in columns/rows, used to
+	// account for the input line wrapping when it's longer than the screen
+	// is wide.
+	Size() (width, height int, err error)
+}
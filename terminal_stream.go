@@ -0,0 +1,53 @@
+package rtprompt
+
+import (
+	"fmt"
+	"io"
+	"sync"
+)
+
+// streamTerminal is the platform-agnostic ANSI backend: it writes cursor
+// control as escape sequences to an arbitrary io.Writer instead of touching
+// a local console. ansiTerminal and winTerminal embed it to share these
+// methods, and route all cursor control and sizing through it (with
+// decodeKeys for input) whenever a Prompt's RawMode is set, since there's then no local tty to put in raw
+// mode or query for a size — eg: an SSH channel, which is already
+// unbuffered and reports its size out of band.
+type streamTerminal struct {
+	out io.Writer
+
+	mu            sync.Mutex
+	width, height int // 0 means unknown until SetSize is called
+}
+
+func newStreamTerminal(out io.Writer) *streamTerminal {
+	return &streamTerminal{out: out}
+}
+
+// SetSize records the terminal's dimensions for Size(), eg: from an SSH
+// window-change request.
+func (t *streamTerminal) SetSize(width, height int) {
+	t.mu.Lock()
+	t.width, t.height = width, height
+	t.mu.Unlock()
+}
+
+func (t *streamTerminal) Size() (int, int, error) {
+	t.mu.Lock()
+	width, height := t.width, t.height
+	t.mu.Unlock()
+
+	if width == 0 {
+		return 0, 0, fmt.Errorf("rtprompt: terminal size unknown")
+	}
+	return width, height, nil
+}
+
+func (t *streamTerminal) CursorUp(n int)    { fmt.Fprintf(t.out, "\033[%dA", n) }
+func (t *streamTerminal) CursorDown(n int)  { fmt.Fprintf(t.out, "\033[%dB", n) }
+func (t *streamTerminal) CursorLeft(n int)  { fmt.Fprintf(t.out, "\033[%dD", n) }
+func (t *streamTerminal) CursorRight(n int) { fmt.Fprintf(t.out, "\033[%dC", n) }
+func (t *streamTerminal) ClearLine()        { fmt.Fprintf(t.out, "\033[2K") }
+func (t *streamTerminal) EraseFromCursor()  { fmt.Fprintf(t.out, "\033[K") }
+func (t *streamTerminal) SaveCursor()       { fmt.Fprintf(t.out, "\033[s") }
+func (t *streamTerminal) RestoreCursor()    { fmt.Fprintf(t.out, "\033[u") }
@@ -0,0 +1,71 @@
+//go:build !windows
+
+package rtprompt
+
+import (
+	"io"
+
+	"github.com/coxley/keyboard"
+	"golang.org/x/term"
+)
+
+// ansiTerminal is the Unix terminal backend. Cursor control is done with
+// plain ANSI escape sequences, which every Unix terminal emulator supports,
+// via the embedded streamTerminal.
+type ansiTerminal struct {
+	*streamTerminal
+
+	oldState *term.State
+	restore  func()
+	remote   bool
+}
+
+func newTerminal(out io.Writer) terminal {
+	return &ansiTerminal{streamTerminal: newStreamTerminal(out)}
+}
+
+func (t *ansiTerminal) Open(in io.Reader, rawMode RawModeFunc) (<-chan keyboard.KeyEvent, error) {
+	if rawMode != nil {
+		restore, err := rawMode()
+		if err != nil {
+			return nil, err
+		}
+		t.restore = restore
+		t.remote = true
+		return decodeKeys(in), nil
+	}
+
+	oldState, err := term.MakeRaw(0)
+	if err != nil {
+		return nil, err
+	}
+	t.oldState = oldState
+
+	keys, err := keyboard.GetKeys(10)
+	if err != nil {
+		term.Restore(0, oldState)
+		return nil, err
+	}
+	return keys, nil
+}
+
+func (t *ansiTerminal) Close() {
+	if t.remote {
+		if t.restore != nil {
+			t.restore()
+		}
+		return
+	}
+
+	keyboard.Close()
+	if t.oldState != nil {
+		term.Restore(0, t.oldState)
+	}
+}
+
+func (t *ansiTerminal) Size() (int, int, error) {
+	if t.remote {
+		return t.streamTerminal.Size()
+	}
+	return term.GetSize(0)
+}
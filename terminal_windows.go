@@ -0,0 +1,192 @@
+//go:build windows
+
+package rtprompt
+
+import (
+	"io"
+	"os"
+	"unsafe"
+
+	"github.com/coxley/keyboard"
+	"golang.org/x/sys/windows"
+)
+
+// golang.org/x/sys/windows doesn't wrap FillConsoleOutputCharacter, so call
+// it directly, the same way the package's own generated wrappers do.
+var (
+	kernel32                        = windows.NewLazySystemDLL("kernel32.dll")
+	procFillConsoleOutputCharacterW = kernel32.NewProc("FillConsoleOutputCharacterW")
+)
+
+func fillConsoleOutputCharacter(handle windows.Handle, ch rune, length uint32, pos windows.Coord) (written uint32, err error) {
+	r1, _, e1 := procFillConsoleOutputCharacterW.Call(
+		uintptr(handle),
+		uintptr(ch),
+		uintptr(length),
+		uintptr(*(*uint32)(unsafe.Pointer(&pos))),
+		uintptr(unsafe.Pointer(&written)),
+	)
+	if r1 == 0 {
+		return written, e1
+	}
+	return written, nil
+}
+
+// winTerminal is the Windows terminal backend. It first tries to enable
+// ENABLE_VIRTUAL_TERMINAL_PROCESSING so modern consoles (Windows Terminal,
+// recent cmd.exe/PowerShell) can be driven with the same ANSI sequences as
+// Unix, via the embedded streamTerminal. Consoles that don't support VT fall
+// back to SetConsoleCursorPosition and FillConsoleOutputCharacter, mirroring
+// peterh/liner's output_windows.go. When rawMode is set there's no local
+// console at all (eg: an SSH channel), so that path always behaves like VT.
+type winTerminal struct {
+	*streamTerminal
+
+	handle   windows.Handle
+	oldMode  uint32
+	vt       bool
+	restore  func()
+	remote   bool
+	savedPos windows.Coord
+	savedOK  bool
+}
+
+func newTerminal(out io.Writer) terminal {
+	return &winTerminal{streamTerminal: newStreamTerminal(out)}
+}
+
+func (t *winTerminal) Open(in io.Reader, rawMode RawModeFunc) (<-chan keyboard.KeyEvent, error) {
+	if rawMode != nil {
+		restore, err := rawMode()
+		if err != nil {
+			return nil, err
+		}
+		t.restore = restore
+		t.remote = true
+		return decodeKeys(in), nil
+	}
+
+	t.handle = windows.Handle(os.Stdout.Fd())
+	if err := windows.GetConsoleMode(t.handle, &t.oldMode); err == nil {
+		mode := t.oldMode | windows.ENABLE_VIRTUAL_TERMINAL_PROCESSING
+		t.vt = windows.SetConsoleMode(t.handle, mode) == nil
+	}
+
+	return keyboard.GetKeys(10)
+}
+
+func (t *winTerminal) Close() {
+	if t.remote {
+		if t.restore != nil {
+			t.restore()
+		}
+		return
+	}
+
+	keyboard.Close()
+	if t.vt {
+		windows.SetConsoleMode(t.handle, t.oldMode)
+	}
+}
+
+func (t *winTerminal) Size() (int, int, error) {
+	if t.remote {
+		return t.streamTerminal.Size()
+	}
+
+	var info windows.ConsoleScreenBufferInfo
+	if err := windows.GetConsoleScreenBufferInfo(t.handle, &info); err != nil {
+		return 0, 0, err
+	}
+	return int(info.Size.X), int(info.Size.Y), nil
+}
+
+func (t *winTerminal) CursorUp(n int)    { t.moveCursor(0, -n) }
+func (t *winTerminal) CursorDown(n int)  { t.moveCursor(0, n) }
+func (t *winTerminal) CursorLeft(n int)  { t.moveCursor(-n, 0) }
+func (t *winTerminal) CursorRight(n int) { t.moveCursor(n, 0) }
+
+func (t *winTerminal) ClearLine() { t.fillLine() }
+
+// EraseFromCursor and the cursor-save/restore pair aren't exposed by the
+// plain console API, so the non-VT fallback implements them itself:
+// FillConsoleOutputCharacter (the same primitive fillLine uses) blanks out
+// to end-of-line, and save/restore track the cursor position in t.savedPos
+// since there's no equivalent of ANSI's cursor stack to lean on.
+func (t *winTerminal) EraseFromCursor() {
+	if t.vt || t.remote {
+		t.streamTerminal.EraseFromCursor()
+		return
+	}
+
+	var info windows.ConsoleScreenBufferInfo
+	if err := windows.GetConsoleScreenBufferInfo(t.handle, &info); err != nil {
+		return
+	}
+	remaining := uint32(info.Size.X - info.CursorPosition.X)
+	fillConsoleOutputCharacter(t.handle, ' ', remaining, info.CursorPosition)
+}
+
+func (t *winTerminal) SaveCursor() {
+	if t.vt || t.remote {
+		t.streamTerminal.SaveCursor()
+		return
+	}
+
+	var info windows.ConsoleScreenBufferInfo
+	if err := windows.GetConsoleScreenBufferInfo(t.handle, &info); err == nil {
+		t.savedPos = info.CursorPosition
+		t.savedOK = true
+	}
+}
+
+func (t *winTerminal) RestoreCursor() {
+	if t.vt || t.remote {
+		t.streamTerminal.RestoreCursor()
+		return
+	}
+
+	if t.savedOK {
+		windows.SetConsoleCursorPosition(t.handle, t.savedPos)
+	}
+}
+
+func (t *winTerminal) moveCursor(dx, dy int) {
+	if t.vt || t.remote {
+		switch {
+		case dy < 0:
+			t.streamTerminal.CursorUp(-dy)
+		case dy > 0:
+			t.streamTerminal.CursorDown(dy)
+		case dx > 0:
+			t.streamTerminal.CursorRight(dx)
+		case dx < 0:
+			t.streamTerminal.CursorLeft(-dx)
+		}
+		return
+	}
+
+	var info windows.ConsoleScreenBufferInfo
+	if err := windows.GetConsoleScreenBufferInfo(t.handle, &info); err != nil {
+		return
+	}
+	pos := windows.Coord{
+		X: info.CursorPosition.X + int16(dx),
+		Y: info.CursorPosition.Y + int16(dy),
+	}
+	windows.SetConsoleCursorPosition(t.handle, pos)
+}
+
+func (t *winTerminal) fillLine() {
+	if t.vt || t.remote {
+		t.streamTerminal.ClearLine()
+		return
+	}
+
+	var info windows.ConsoleScreenBufferInfo
+	if err := windows.GetConsoleScreenBufferInfo(t.handle, &info); err != nil {
+		return
+	}
+	start := windows.Coord{X: 0, Y: info.CursorPosition.Y}
+	fillConsoleOutputCharacter(t.handle, ' ', uint32(info.Size.X), start)
+}
@@ -0,0 +1,71 @@
+package rtprompt
+
+// runeWidth returns how many terminal columns r occupies: 0 for combining
+// marks and other zero-width characters, 2 for East Asian wide/fullwidth
+// runes, 1 otherwise.
+//
+// This covers the common ranges rather than the full Unicode East Asian
+// Width + combining-class tables, similar to the width handling in
+// peterh/liner. Good enough for prompt input.
+func runeWidth(r rune) int {
+	switch {
+	case r == 0:
+		return 0
+	case isCombining(r):
+		return 0
+	case isWide(r):
+		return 2
+	default:
+		return 1
+	}
+}
+
+// runesWidth sums the column width of every rune in rs.
+func runesWidth(rs []rune) int {
+	w := 0
+	for _, r := range rs {
+		w += runeWidth(r)
+	}
+	return w
+}
+
+// isCombining reports whether r is a zero-width combining mark.
+func isCombining(r rune) bool {
+	switch {
+	case r >= 0x0300 && r <= 0x036F: // combining diacritical marks
+		return true
+	case r >= 0x200B && r <= 0x200F: // zero-width space/joiners
+		return true
+	case r >= 0x20D0 && r <= 0x20FF: // combining diacritical marks for symbols
+		return true
+	case r >= 0xFE00 && r <= 0xFE0F: // variation selectors
+		return true
+	case r >= 0x1AB0 && r <= 0x1AFF: // combining diacritical marks extended
+		return true
+	}
+	return false
+}
+
+// isWide reports whether r is East Asian Wide/Fullwidth, per the ranges most
+// terminal emulators render at double width.
+func isWide(r rune) bool {
+	switch {
+	case r >= 0x1100 && r <= 0x115F: // Hangul Jamo
+		return true
+	case r >= 0x2E80 && r <= 0xA4CF && r != 0x303F: // CJK radicals .. Yi
+		return true
+	case r >= 0xAC00 && r <= 0xD7A3: // Hangul syllables
+		return true
+	case r >= 0xF900 && r <= 0xFAFF: // CJK compatibility ideographs
+		return true
+	case r >= 0xFF00 && r <= 0xFF60: // fullwidth forms
+		return true
+	case r >= 0xFFE0 && r <= 0xFFE6: // fullwidth signs
+		return true
+	case r >= 0x20000 && r <= 0x3FFFD: // CJK unified ideographs extensions
+		return true
+	case r >= 0x1F300 && r <= 0x1FAFF: // emoji and pictographs
+		return true
+	}
+	return false
+}